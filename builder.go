@@ -8,18 +8,22 @@ import (
 )
 
 type Builder struct {
-	scheme      string
-	domain      string
-	port        int
-	credentials *credentials
-	path        []string
-	query       map[string][]string
-	anchor      string
+	scheme           string
+	schemeSet        bool
+	domain           string
+	port             int
+	credentials      *credentials
+	path             []string
+	query            map[string][]string
+	anchor           string
+	strict           bool
+	allowDotSegments bool
 }
 
 type credentials struct {
-	user     string
-	password string
+	user        string
+	password    string
+	hasPassword bool
 }
 
 // New creates a new empty Builder instance. Scheme set to "http" by default.
@@ -34,18 +38,21 @@ func New() *Builder {
 // Passed value is not validated.
 func (b *Builder) WithScheme(scheme string) *Builder {
 	b.scheme = strings.Trim(scheme, ":/")
+	b.schemeSet = true
 	return b
 }
 
 // WithSchemeHTTP sets the URL scheme to HTTP.
 func (b *Builder) WithSchemeHTTP() *Builder {
 	b.scheme = "http"
+	b.schemeSet = true
 	return b
 }
 
 // WithSchemeHTTPS sets the URL scheme to HTTPS.
 func (b *Builder) WithSchemeHTTPS() *Builder {
 	b.scheme = "https"
+	b.schemeSet = true
 	return b
 }
 
@@ -71,6 +78,22 @@ func (b *Builder) WithIPv6(address string) *Builder {
 	return b
 }
 
+// escapeIPv6Zone percent-encodes the "%" introducing an IPv6 zone ID (e.g.
+// "[fe80::1%eth0]") as "%25", per RFC 6874, so that url.Parse accepts it
+// instead of mistaking the zone name for a malformed percent-encoding.
+func escapeIPv6Zone(domain string) string {
+	if !strings.HasPrefix(domain, "[") || !strings.HasSuffix(domain, "]") {
+		return domain
+	}
+
+	inner := domain[1 : len(domain)-1]
+	if idx := strings.IndexByte(inner, '%'); idx != -1 && !strings.HasPrefix(inner[idx:], "%25") {
+		inner = inner[:idx] + "%25" + inner[idx+1:]
+	}
+
+	return "[" + inner + "]"
+}
+
 // WithPort sets the port number.
 // Build will return an error if port not in [1, 65535] range.
 func (b *Builder) WithPort(port int) *Builder {
@@ -82,8 +105,9 @@ func (b *Builder) WithPort(port int) *Builder {
 // Build will return an error if one of the parameters is empty.
 func (b *Builder) WithCredentials(user, password string) *Builder {
 	b.credentials = &credentials{
-		user:     user,
-		password: password,
+		user:        user,
+		password:    password,
+		hasPassword: true,
 	}
 	return b
 }
@@ -112,45 +136,109 @@ func (b *Builder) WithAnchor(anchor string) *Builder {
 // Build constructs the final URL string based on the provided data.
 // Returns an error if data is invalid.
 func (b *Builder) Build() (string, error) {
-	if b.domain == "" {
+	traits := schemeTraitsFor(b.scheme)
+	requiresAuthority := traits.requiresAuthority
+	allowsUserinfo := traits.allowsUserinfo
+	defaultPort := traits.defaultPort
+
+	if profile, ok := schemeProfileFor(b.scheme); ok {
+		if err := profile.ValidatePath(b.path); err != nil {
+			return "", err
+		}
+	}
+
+	if b.domain == "" && !traits.allowsEmptyAuthority {
 		return "", fmt.Errorf("domain is required")
 	}
 
+	if b.strict {
+		if err := b.validateStrict(); err != nil {
+			return "", err
+		}
+	}
+
 	// check given domain
 	// todo: can't detect if given ipv6 contains port, so result string might be broken.
-	if strings.Contains(b.domain, "/") || // assume domain contains scheme
-		strings.Count(b.domain, ":") == 1 { // assume domain contains port (valid ipv6 have at least 2 colons)
+	if b.domain != "" && (strings.Contains(b.domain, "/") || // assume domain contains scheme
+		strings.Count(b.domain, ":") == 1) { // assume domain contains port (valid ipv6 have at least 2 colons)
 		return "", fmt.Errorf("domain contains forbidden symbols")
 	}
 
-	rawBaseUrl := fmt.Sprintf("%s://%s", b.scheme, b.domain)
+	if b.port > 65535 {
+		return "", fmt.Errorf("port must be in range [1, 65535]")
+	}
+
+	var u *url.URL
+
+	if requiresAuthority {
+		rawBaseUrl := fmt.Sprintf("%s://%s", b.scheme, escapeIPv6Zone(b.domain))
 
-	if b.port > 0 {
-		if b.port > 65535 {
-			return "", fmt.Errorf("port must be in range [1, 65535]")
+		if b.port > 0 && b.port != defaultPort {
+			rawBaseUrl = fmt.Sprintf("%s:%d", rawBaseUrl, b.port)
 		}
-		rawBaseUrl = fmt.Sprintf("%s:%d", rawBaseUrl, b.port)
-	}
 
-	u, err := url.Parse(rawBaseUrl)
-	if err != nil {
-		return "", err
-	}
+		var err error
+		u, err = url.Parse(rawBaseUrl)
+		if err != nil {
+			return "", err
+		}
+
+		if b.credentials != nil {
+			if b.credentials.user == "" {
+				return "", fmt.Errorf("user not set")
+			}
+
+			if !allowsUserinfo {
+				return "", fmt.Errorf("scheme %q does not allow userinfo", b.scheme)
+			}
 
-	if b.credentials != nil {
-		if b.credentials.user == "" {
-			return "", fmt.Errorf("user not set")
+			if b.credentials.hasPassword {
+				if b.credentials.password == "" {
+					return "", fmt.Errorf("password not set")
+				}
+				u.User = url.UserPassword(b.credentials.user, b.credentials.password)
+			} else {
+				u.User = url.User(b.credentials.user)
+			}
 		}
 
-		if b.credentials.password == "" {
-			return "", fmt.Errorf("password not set")
+		if b.domain == "" {
+			u.Path = "/"
 		}
 
-		u.User = url.UserPassword(b.credentials.user, b.credentials.password)
-	}
+		if len(b.path) > 0 {
+			u = u.JoinPath(b.path...)
+		}
+	} else {
+		opaque := b.domain
+
+		if b.credentials != nil {
+			if b.credentials.user == "" {
+				return "", fmt.Errorf("user not set")
+			}
+
+			if allowsUserinfo {
+				if b.credentials.hasPassword {
+					if b.credentials.password == "" {
+						return "", fmt.Errorf("password not set")
+					}
+					opaque = fmt.Sprintf("%s:%s@%s", b.credentials.user, b.credentials.password, b.domain)
+				} else {
+					opaque = fmt.Sprintf("%s@%s", b.credentials.user, b.domain)
+				}
+			} else {
+				if b.credentials.hasPassword && b.credentials.password != "" {
+					return "", fmt.Errorf("scheme %q does not allow password credentials", b.scheme)
+				}
+				opaque = fmt.Sprintf("%s@%s", b.credentials.user, b.domain)
+			}
+		}
+
+		if b.port > 0 && b.port != defaultPort {
+			opaque = fmt.Sprintf("%s:%d", opaque, b.port)
+		}
 
-	if len(b.path) > 0 {
-		u = u.JoinPath(b.path...)
+		u = &url.URL{Scheme: b.scheme, Opaque: opaque}
 	}
 
 	for k, v := range b.query {
@@ -0,0 +1,66 @@
+package url_builder
+
+import (
+	"os"
+	"strconv"
+)
+
+// WithDefaults fills in scheme, port, credentials and domain from other,
+// for any of those fields that are not already set on b. Existing
+// non-empty/non-zero fields on b are left untouched; scheme is considered
+// set once WithScheme/WithSchemeHTTP/WithSchemeHTTPS has been called, since
+// New already gives every Builder a non-empty "http" default.
+func (b *Builder) WithDefaults(other *Builder) *Builder {
+	if other == nil {
+		return b
+	}
+
+	if !b.schemeSet {
+		b.scheme = other.scheme
+		b.schemeSet = other.schemeSet
+	}
+
+	if b.domain == "" {
+		b.domain = other.domain
+	}
+
+	if b.port == 0 {
+		b.port = other.port
+	}
+
+	if b.credentials == nil {
+		b.credentials = other.credentials
+	}
+
+	return b
+}
+
+// WithEnv reads "${prefix}_USER", "${prefix}_PASSWORD", "${prefix}_HOST"
+// and "${prefix}_PORT" from the environment and applies each one only if
+// the corresponding field on b is not already set, so an operator can
+// override credentials or host without editing code.
+func (b *Builder) WithEnv(prefix string) *Builder {
+	if b.domain == "" {
+		if host := os.Getenv(prefix + "_HOST"); host != "" {
+			b.domain = host
+		}
+	}
+
+	if b.port == 0 {
+		if portStr := os.Getenv(prefix + "_PORT"); portStr != "" {
+			if port, err := strconv.Atoi(portStr); err == nil {
+				b.port = port
+			}
+		}
+	}
+
+	if b.credentials == nil {
+		user, hasUser := os.LookupEnv(prefix + "_USER")
+		password, hasPassword := os.LookupEnv(prefix + "_PASSWORD")
+		if hasUser || hasPassword {
+			b.credentials = &credentials{user: user, password: password, hasPassword: hasPassword}
+		}
+	}
+
+	return b
+}
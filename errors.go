@@ -0,0 +1,20 @@
+package url_builder
+
+import "fmt"
+
+// BuildError is returned by Build for a field that fails strict validation
+// (see WithStrict), allowing callers to programmatically inspect which
+// field failed and why. Build's other errors are not of this type.
+type BuildError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("%s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+func newBuildError(field, value, reason string) *BuildError {
+	return &BuildError{Field: field, Value: value, Reason: reason}
+}
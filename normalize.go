@@ -0,0 +1,201 @@
+package url_builder
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	defaultPortsMu sync.RWMutex
+	defaultPorts   = map[string]int{
+		"http":  80,
+		"https": 443,
+		"ftp":   21,
+	}
+)
+
+// RegisterDefaultPort registers the default port for scheme, so that
+// Normalize knows to strip it from a Builder's output. http (80), https
+// (443) and ftp (21) are registered by default. A SchemeProfile registered
+// for the same scheme with a non-zero DefaultPort takes precedence.
+func RegisterDefaultPort(scheme string, port int) {
+	defaultPortsMu.Lock()
+	defer defaultPortsMu.Unlock()
+	defaultPorts[strings.ToLower(scheme)] = port
+}
+
+func defaultPortFor(scheme string) (int, bool) {
+	if profile, ok := schemeProfileFor(scheme); ok {
+		if port := profile.DefaultPort(); port > 0 {
+			return port, true
+		}
+	}
+
+	defaultPortsMu.RLock()
+	defer defaultPortsMu.RUnlock()
+	port, ok := defaultPorts[strings.ToLower(scheme)]
+	return port, ok
+}
+
+// Normalize applies RFC 3986 section 6 syntax-based normalization: the
+// scheme and host are lowercased, percent-encoded hex triplets are
+// uppercased, percent-encodings of unreserved characters are decoded, the
+// scheme's default port (from its SchemeProfile, else from
+// RegisterDefaultPort) is dropped, and "." / ".." path segments are
+// resolved.
+func (b *Builder) Normalize() *Builder {
+	b.scheme = strings.ToLower(b.scheme)
+
+	if b.domain != "" {
+		if strings.HasPrefix(b.domain, "[") && strings.HasSuffix(b.domain, "]") {
+			b.domain = "[" + strings.ToLower(strings.Trim(b.domain, "[]")) + "]"
+		} else {
+			b.domain = normalizeHostComponent(b.domain)
+		}
+	}
+
+	if port, ok := defaultPortFor(b.scheme); ok && b.port == port {
+		b.port = 0
+	}
+
+	segments := make([]string, len(b.path))
+	for i, seg := range b.path {
+		segments[i] = normalizePercentEncoding(seg)
+	}
+	b.path = resolveDotSegments(segments)
+
+	for k, values := range b.query {
+		decoded := make([]string, len(values))
+		for i, v := range values {
+			decoded[i] = normalizePercentEncoding(v)
+		}
+		b.query[k] = decoded
+	}
+
+	if b.anchor != "" {
+		b.anchor = normalizePercentEncoding(b.anchor)
+	}
+
+	return b
+}
+
+// Equal parses a and b, normalizes both and reports whether they refer to
+// the same resource. It returns false if either string fails to parse or
+// to rebuild.
+func Equal(a, b string) bool {
+	sa, err := normalizedBuild(a)
+	if err != nil {
+		return false
+	}
+
+	sb, err := normalizedBuild(b)
+	if err != nil {
+		return false
+	}
+
+	return sa == sb
+}
+
+func normalizedBuild(rawurl string) (string, error) {
+	builder, err := Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	return builder.Normalize().Build()
+}
+
+func resolveDotSegments(segments []string) []string {
+	out := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		switch seg {
+		case ".":
+		case "..":
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		default:
+			out = append(out, seg)
+		}
+	}
+	return out
+}
+
+func isNormalizeUnreservedByte(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+		b == '-' || b == '.' || b == '_' || b == '~'
+}
+
+func isNormalizeHexDigit(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'A' && b <= 'F') || (b >= 'a' && b <= 'f')
+}
+
+// normalizeHostComponent applies the same percent-encoding normalization as
+// normalizePercentEncoding, plus lowercasing of the literal (non
+// percent-encoded) bytes, so that uppercasing hex triplets isn't undone by
+// a blanket strings.ToLower over the whole host.
+func normalizeHostComponent(s string) string {
+	var sb strings.Builder
+
+	i := 0
+	for i < len(s) {
+		if s[i] == '%' && i+2 < len(s) && isNormalizeHexDigit(s[i+1]) && isNormalizeHexDigit(s[i+2]) {
+			hex := s[i+1 : i+3]
+			decoded, _ := strconv.ParseUint(hex, 16, 8)
+			c := byte(decoded)
+
+			if isNormalizeUnreservedByte(c) {
+				sb.WriteByte(toLowerByte(c))
+			} else {
+				sb.WriteByte('%')
+				sb.WriteString(strings.ToUpper(hex))
+			}
+
+			i += 3
+			continue
+		}
+
+		sb.WriteByte(toLowerByte(s[i]))
+		i++
+	}
+
+	return sb.String()
+}
+
+func toLowerByte(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + ('a' - 'A')
+	}
+	return b
+}
+
+// normalizePercentEncoding uppercases percent-encoded hex triplets and
+// decodes those that represent an unreserved character, per RFC 3986
+// section 6.2.2.1 and 6.2.2.2.
+func normalizePercentEncoding(s string) string {
+	var sb strings.Builder
+
+	i := 0
+	for i < len(s) {
+		if s[i] == '%' && i+2 < len(s) && isNormalizeHexDigit(s[i+1]) && isNormalizeHexDigit(s[i+2]) {
+			hex := s[i+1 : i+3]
+			decoded, _ := strconv.ParseUint(hex, 16, 8)
+			c := byte(decoded)
+
+			if isNormalizeUnreservedByte(c) {
+				sb.WriteByte(c)
+			} else {
+				sb.WriteByte('%')
+				sb.WriteString(strings.ToUpper(hex))
+			}
+
+			i += 3
+			continue
+		}
+
+		sb.WriteByte(s[i])
+		i++
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,121 @@
+package url_builder
+
+import "testing"
+
+func TestBuilder_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    string
+	}{
+		{
+			name:    "Lowercases scheme and host",
+			builder: New().WithScheme("HTTP").WithDomain("Example.COM"),
+			want:    "http://example.com",
+		},
+		{
+			name:    "Strips default http port",
+			builder: New().WithDomain("example.com").WithPort(80),
+			want:    "http://example.com",
+		},
+		{
+			name:    "Keeps non-default port",
+			builder: New().WithDomain("example.com").WithPort(8080),
+			want:    "http://example.com:8080",
+		},
+		{
+			name:    "Uppercases percent-encoded triplets",
+			builder: New().WithDomain("example.com").WithPath("foo%2fbar"),
+			want:    "http://example.com/foo%2Fbar",
+		},
+		{
+			name:    "Decodes unreserved percent-encodings",
+			builder: New().WithDomain("example.com").WithPath("foo%7Ebar"),
+			want:    "http://example.com/foo~bar",
+		},
+		{
+			name:    "Resolves dot segments",
+			builder: New().WithDomain("example.com").WithPath("a", "b", "..", "c"),
+			want:    "http://example.com/a/c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Normalize().Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// net/url itself can't round-trip a host containing a percent-encoded
+// reserved character through Build, so this checks the normalized domain
+// field directly rather than going through Build.
+func TestBuilder_Normalize_DomainCase(t *testing.T) {
+	b := New().WithDomain("ex%2fmple.COM").Normalize()
+
+	want := "ex%2Fmple.com"
+	if b.domain != want {
+		t.Errorf("got domain = %v, want %v", b.domain, want)
+	}
+}
+
+func TestRegisterDefaultPort_CaseInsensitive(t *testing.T) {
+	RegisterDefaultPort("ACME", 7000)
+
+	got, err := New().WithScheme("ACME").WithDomain("example.com").WithPort(7000).Normalize().Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	want := "acme://example.com"
+	if got != want {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{
+			name: "Default port omitted vs explicit",
+			a:    "http://example.com",
+			b:    "http://example.com:80",
+			want: true,
+		},
+		{
+			name: "Case-insensitive scheme and host",
+			a:    "HTTP://Example.com",
+			b:    "http://example.com",
+			want: true,
+		},
+		{
+			name: "Different hosts",
+			a:    "http://example.com",
+			b:    "http://example.org",
+			want: false,
+		},
+		{
+			name: "Invalid url",
+			a:    "http://[::1",
+			b:    "http://example.com",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("Equal(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package url_builder
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Parse decomposes rawurl into a Builder populated with its scheme,
+// credentials, host, port, path segments, query parameters and fragment.
+// Calling Build on the result gives an equivalent URL, not always the same
+// string: Build omits the scheme's default port, drops leading and
+// trailing slashes of the path and sorts query keys. Build returns an
+// error for a URL with an empty query value, and for an opaque URL such as
+// "mailto:user@example.com", whose domain From leaves empty.
+func Parse(rawurl string) (*Builder, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+
+	return From(u), nil
+}
+
+// From builds a Builder from an already-parsed *url.URL, following the
+// same field mapping as Parse.
+func From(u *url.URL) *Builder {
+	b := New()
+
+	if u.Scheme != "" {
+		b.scheme = u.Scheme
+		b.schemeSet = true
+	}
+
+	host := u.Hostname()
+	if host != "" {
+		if ip, err := netip.ParseAddr(host); err == nil && ip.Is6() {
+			b.domain = fmt.Sprintf("[%s]", host)
+		} else {
+			b.domain = host
+		}
+	}
+
+	if portStr := u.Port(); portStr != "" {
+		if port, err := strconv.Atoi(portStr); err == nil {
+			b.port = port
+		}
+	}
+
+	if u.User != nil {
+		password, hasPassword := u.User.Password()
+		b.credentials = &credentials{
+			user:        u.User.Username(),
+			password:    password,
+			hasPassword: hasPassword,
+		}
+	}
+
+	if trimmed := strings.Trim(u.EscapedPath(), "/"); trimmed != "" {
+		b.path = strings.Split(trimmed, "/")
+	}
+
+	for k, v := range u.Query() {
+		b.query[k] = append(b.query[k], v...)
+	}
+
+	b.anchor = u.Fragment
+
+	return b
+}
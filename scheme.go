@@ -0,0 +1,102 @@
+package url_builder
+
+import (
+	"strings"
+	"sync"
+)
+
+// SchemeProfile describes how Build should treat a particular URL scheme:
+// its conventional default port, whether it uses the "//" authority
+// syntax at all, whether it permits userinfo ("user:pass@"), and any
+// scheme-specific path constraints.
+type SchemeProfile interface {
+	DefaultPort() int
+	RequiresAuthority() bool
+	AllowsUserinfo() bool
+	ValidatePath(segments []string) error
+}
+
+// basicSchemeProfile is a SchemeProfile with no path constraints, used by
+// most of the built-in schemes.
+type basicSchemeProfile struct {
+	defaultPort       int
+	requiresAuthority bool
+	allowsUserinfo    bool
+}
+
+func (p basicSchemeProfile) DefaultPort() int            { return p.defaultPort }
+func (p basicSchemeProfile) RequiresAuthority() bool     { return p.requiresAuthority }
+func (p basicSchemeProfile) AllowsUserinfo() bool        { return p.allowsUserinfo }
+func (p basicSchemeProfile) ValidatePath([]string) error { return nil }
+
+// emptyAuthorityProfile additionally allows Build to omit the host, for
+// schemes whose authority component is conventionally empty (e.g. "file").
+// It is not part of SchemeProfile since most schemes require a host.
+type emptyAuthorityProfile interface {
+	AllowsEmptyAuthority() bool
+}
+
+type fileSchemeProfile struct {
+	basicSchemeProfile
+}
+
+func (fileSchemeProfile) AllowsEmptyAuthority() bool { return true }
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]SchemeProfile{
+		"http":   basicSchemeProfile{defaultPort: 80, requiresAuthority: true, allowsUserinfo: true},
+		"https":  basicSchemeProfile{defaultPort: 443, requiresAuthority: true, allowsUserinfo: true},
+		"ftp":    basicSchemeProfile{defaultPort: 21, requiresAuthority: true, allowsUserinfo: true},
+		"ssh":    basicSchemeProfile{defaultPort: 22, requiresAuthority: true, allowsUserinfo: true},
+		"ws":     basicSchemeProfile{defaultPort: 80, requiresAuthority: true, allowsUserinfo: true},
+		"wss":    basicSchemeProfile{defaultPort: 443, requiresAuthority: true, allowsUserinfo: true},
+		"mailto": basicSchemeProfile{requiresAuthority: false, allowsUserinfo: false},
+		"file":   fileSchemeProfile{basicSchemeProfile{requiresAuthority: true, allowsUserinfo: false}},
+	}
+)
+
+// RegisterScheme registers (or overrides) the SchemeProfile Build consults
+// for the given scheme name, so that Build can apply scheme-specific
+// defaults and validation beyond the built-in http/https/ftp/ssh/ws/wss/
+// mailto/file profiles.
+func RegisterScheme(name string, p SchemeProfile) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	schemes[strings.ToLower(name)] = p
+}
+
+func schemeProfileFor(name string) (SchemeProfile, bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	p, ok := schemes[strings.ToLower(name)]
+	return p, ok
+}
+
+// schemeTraits collects the Build-relevant traits of a scheme's profile,
+// falling back to the defaults for an unregistered scheme.
+type schemeTraits struct {
+	requiresAuthority    bool
+	allowsUserinfo       bool
+	allowsEmptyAuthority bool
+	defaultPort          int
+}
+
+func schemeTraitsFor(name string) schemeTraits {
+	traits := schemeTraits{requiresAuthority: true, allowsUserinfo: true}
+
+	profile, ok := schemeProfileFor(name)
+	if !ok {
+		return traits
+	}
+
+	traits.requiresAuthority = profile.RequiresAuthority()
+	traits.allowsUserinfo = profile.AllowsUserinfo()
+	traits.defaultPort = profile.DefaultPort()
+
+	if ea, ok := profile.(emptyAuthorityProfile); ok {
+		traits.allowsEmptyAuthority = ea.AllowsEmptyAuthority()
+	}
+
+	return traits
+}
@@ -0,0 +1,86 @@
+package url_builder
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBuilder_Build_SchemeProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Omits default http port",
+			builder: New().WithDomain("example.com").WithPort(80),
+			want:    "http://example.com",
+		},
+		{
+			name:    "Keeps non-default http port",
+			builder: New().WithDomain("example.com").WithPort(8080),
+			want:    "http://example.com:8080",
+		},
+		{
+			name:    "Mailto with local part only",
+			builder: New().WithScheme("mailto").WithDomain("example.com").WithCredentials("user", ""),
+			want:    "mailto:user@example.com",
+		},
+		{
+			name:    "File with empty authority",
+			builder: New().WithScheme("file").WithPath("etc", "passwd"),
+			want:    "file:///etc/passwd",
+		},
+		{
+			name:    "Mailto rejects password",
+			builder: New().WithScheme("mailto").WithDomain("example.com").WithCredentials("user", "pass"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type testSchemeProfile struct {
+	basicSchemeProfile
+}
+
+func (testSchemeProfile) ValidatePath(segments []string) error {
+	for _, s := range segments {
+		if s == "admin" {
+			return fmt.Errorf("path segment %q is not allowed for this scheme", s)
+		}
+	}
+	return nil
+}
+
+func TestRegisterScheme(t *testing.T) {
+	RegisterScheme("acme", testSchemeProfile{basicSchemeProfile{defaultPort: 1234, requiresAuthority: true, allowsUserinfo: true}})
+
+	got, err := New().WithScheme("acme").WithDomain("example.com").WithPort(1234).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if want := "acme://example.com"; got != want {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+
+	_, err = New().WithScheme("acme").WithDomain("example.com").WithPath("admin").Build()
+	if err == nil {
+		t.Error("expected ValidatePath to reject the \"admin\" segment")
+	}
+}
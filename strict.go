@@ -0,0 +1,113 @@
+package url_builder
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+var (
+	schemeStrictRe  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+\-.]*$`)
+	regNameStrictRe = regexp.MustCompile(`^(?:[A-Za-z0-9\-._~!$&'()*+,;=]|%[0-9A-Fa-f]{2})*$`)
+	pcharStrictRe   = regexp.MustCompile(`^(?:[A-Za-z0-9\-._~!$&'()*+,;=:@]|%[0-9A-Fa-f]{2})*$`)
+)
+
+// WithStrict enables RFC 3986-conformant validation in Build. A field that
+// fails it is reported as a *BuildError. Build's other checks still run
+// and return plain errors: a missing domain is reported before the strict
+// checks, and userinfo the scheme disallows after them.
+func (b *Builder) WithStrict() *Builder {
+	b.strict = true
+	return b
+}
+
+// WithDotSegmentsAllowed permits "." and ".." path segments to pass strict
+// validation. By default WithStrict rejects them; once allowed, Build
+// resolves them as net/url.URL.JoinPath does (".." removes the segment
+// before it).
+func (b *Builder) WithDotSegmentsAllowed() *Builder {
+	b.allowDotSegments = true
+	return b
+}
+
+// validateStrict performs RFC 3986-conformant validation of the builder's
+// fields, returning a *BuildError describing the first violation found.
+func (b *Builder) validateStrict() error {
+	if !schemeStrictRe.MatchString(b.scheme) {
+		return newBuildError("scheme", b.scheme, "must match ALPHA *( ALPHA / DIGIT / \"+\" / \"-\" / \".\" )")
+	}
+
+	if err := validateHostStrict(b.domain, schemeTraitsFor(b.scheme).allowsEmptyAuthority); err != nil {
+		return err
+	}
+
+	if b.port != 0 && (b.port < 1 || b.port > 65535) {
+		return newBuildError("port", fmt.Sprint(b.port), "must be in range [1, 65535]")
+	}
+
+	for _, segment := range b.path {
+		if segment == "" {
+			continue
+		}
+
+		if !b.allowDotSegments && (segment == "." || segment == "..") {
+			return newBuildError("path", segment, "dot segments are not allowed")
+		}
+
+		if strings.Contains(segment, "/") {
+			return newBuildError("path", segment, "segment must not contain \"/\"")
+		}
+
+		if !pcharStrictRe.MatchString(segment) {
+			return newBuildError("path", segment, "contains characters outside pchar that are not percent-encoded")
+		}
+	}
+
+	for k, values := range b.query {
+		if k == "" {
+			return newBuildError("query", "", "key must not be empty")
+		}
+		for _, v := range values {
+			if v == "" {
+				return newBuildError("query", k, "value must not be empty")
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateHostStrict validates domain as either an IPv4/IPv6 literal (via
+// net/netip, including zone IDs) or an RFC 3986 reg-name. An empty domain
+// is only accepted when the active scheme profile allows an empty
+// authority (e.g. "file").
+func validateHostStrict(domain string, allowsEmptyAuthority bool) error {
+	if domain == "" {
+		if allowsEmptyAuthority {
+			return nil
+		}
+		return newBuildError("domain", domain, "must not be empty")
+	}
+
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		addr := strings.Trim(domain, "[]")
+		if _, err := netip.ParseAddr(addr); err != nil {
+			return newBuildError("domain", domain, "not a valid IPv6 address")
+		}
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(domain); err == nil {
+		if !addr.Is4() {
+			return newBuildError("domain", domain, "IPv6 literals must be enclosed in brackets")
+		}
+		return nil
+	}
+
+	if !regNameStrictRe.MatchString(domain) {
+		return newBuildError("domain", domain, "contains characters forbidden in a reg-name")
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package url_builder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuilder_BuildStrict(t *testing.T) {
+	tests := []struct {
+		name      string
+		builder   *Builder
+		want      string
+		wantErr   bool
+		wantField string
+	}{
+		{
+			name:    "Valid reg-name host",
+			builder: New().WithStrict().WithDomain("example.com"),
+			want:    "http://example.com",
+		},
+		{
+			name:    "Valid IPv4",
+			builder: New().WithStrict().WithIPv4("192.0.2.1"),
+			want:    "http://192.0.2.1",
+		},
+		{
+			name:    "Valid IPv6",
+			builder: New().WithStrict().WithIPv6("::1"),
+			want:    "http://[::1]",
+		},
+		{
+			name:      "Invalid IPv6",
+			builder:   New().WithStrict().WithIPv6("not-an-ip"),
+			wantErr:   true,
+			wantField: "domain",
+		},
+		{
+			name:      "Invalid scheme",
+			builder:   New().WithStrict().WithScheme("1http").WithDomain("example.com"),
+			wantErr:   true,
+			wantField: "scheme",
+		},
+		{
+			name:      "Forbidden reg-name characters",
+			builder:   New().WithStrict().WithDomain("exa mple.com"),
+			wantErr:   true,
+			wantField: "domain",
+		},
+		{
+			name:      "Dot segment rejected by default",
+			builder:   New().WithStrict().WithDomain("example.com").WithPath("..", "etc"),
+			wantErr:   true,
+			wantField: "path",
+		},
+		{
+			name:    "Dot segment allowed explicitly",
+			builder: New().WithStrict().WithDomain("example.com").WithDotSegmentsAllowed().WithPath("..", "etc"),
+			want:    "http://example.com/etc",
+		},
+		{
+			name:    "Empty authority allowed for file scheme",
+			builder: New().WithScheme("file").WithStrict().WithPath("etc", "passwd"),
+			want:    "file:///etc/passwd",
+		},
+		{
+			name:    "Valid IPv6 zone ID",
+			builder: New().WithStrict().WithIPv6("fe80::1%eth0"),
+			want:    "http://[fe80::1%25eth0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				var buildErr *BuildError
+				if !errors.As(err, &buildErr) {
+					t.Errorf("expected *BuildError, got %T", err)
+					return
+				}
+				if buildErr.Field != tt.wantField {
+					t.Errorf("got field = %v, want %v", buildErr.Field, tt.wantField)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
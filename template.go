@@ -0,0 +1,376 @@
+package url_builder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Template is a parsed RFC 6570 URI Template, such as
+// "/users/{id}/posts{?tag,limit}". Use ParseTemplate to create one and
+// Expand or Builder to substitute variables.
+type Template struct {
+	raw   string
+	parts []templatePart
+}
+
+type templatePart struct {
+	literal string
+	isExpr  bool
+	op      byte
+	vars    []templateVar
+}
+
+type templateVar struct {
+	name    string
+	explode bool
+	prefix  int
+}
+
+var templateVarNameRe = regexp.MustCompile(`^[A-Za-z0-9_.%]+$`)
+
+// ParseTemplate parses a URI Template pattern, validating the syntax of its
+// expressions ("{...}") without resolving any variables.
+func ParseTemplate(pattern string) (*Template, error) {
+	var parts []templatePart
+
+	i := 0
+	for i < len(pattern) {
+		if pattern[i] == '{' {
+			end := strings.IndexByte(pattern[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated expression in template %q", pattern)
+			}
+
+			part, err := parseTemplateExpression(pattern[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, part)
+			i += end + 1
+			continue
+		}
+
+		j := i
+		for j < len(pattern) && pattern[j] != '{' {
+			j++
+		}
+		parts = append(parts, templatePart{literal: pattern[i:j]})
+		i = j
+	}
+
+	return &Template{raw: pattern, parts: parts}, nil
+}
+
+func parseTemplateExpression(expr string) (templatePart, error) {
+	if expr == "" {
+		return templatePart{}, fmt.Errorf("empty expression in template")
+	}
+
+	var op byte
+	rest := expr
+	switch expr[0] {
+	case '+', '#', '.', '/', ';', '?', '&':
+		op = expr[0]
+		rest = expr[1:]
+	}
+
+	if rest == "" {
+		return templatePart{}, fmt.Errorf("expression %q has no variables", expr)
+	}
+
+	rawVars := strings.Split(rest, ",")
+	vars := make([]templateVar, 0, len(rawVars))
+	for _, rv := range rawVars {
+		tv, err := parseTemplateVarSpec(rv)
+		if err != nil {
+			return templatePart{}, err
+		}
+		vars = append(vars, tv)
+	}
+
+	return templatePart{isExpr: true, op: op, vars: vars}, nil
+}
+
+func parseTemplateVarSpec(s string) (templateVar, error) {
+	if s == "" {
+		return templateVar{}, fmt.Errorf("empty variable name in template expression")
+	}
+
+	if strings.HasSuffix(s, "*") {
+		name := s[:len(s)-1]
+		if !templateVarNameRe.MatchString(name) {
+			return templateVar{}, fmt.Errorf("invalid variable name %q", name)
+		}
+		return templateVar{name: name, explode: true}, nil
+	}
+
+	if idx := strings.IndexByte(s, ':'); idx != -1 {
+		name := s[:idx]
+		prefix, err := strconv.Atoi(s[idx+1:])
+		if err != nil || prefix <= 0 {
+			return templateVar{}, fmt.Errorf("invalid prefix modifier in %q", s)
+		}
+		if !templateVarNameRe.MatchString(name) {
+			return templateVar{}, fmt.Errorf("invalid variable name %q", name)
+		}
+		return templateVar{name: name, prefix: prefix}, nil
+	}
+
+	if !templateVarNameRe.MatchString(s) {
+		return templateVar{}, fmt.Errorf("invalid variable name %q", s)
+	}
+
+	return templateVar{name: s}, nil
+}
+
+// templateOperator holds the RFC 6570 output rules for an expression
+// operator: the string prepended once before the first substituted
+// variable, the separator joining multiple variables, whether variables
+// are rendered as name=value pairs, the suffix used for a named but empty
+// value, and whether reserved characters pass through unescaped.
+type templateOperator struct {
+	first         string
+	sep           string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+}
+
+func templateOperatorFor(op byte) templateOperator {
+	switch op {
+	case '+':
+		return templateOperator{sep: ",", allowReserved: true}
+	case '#':
+		return templateOperator{first: "#", sep: ",", allowReserved: true}
+	case '.':
+		return templateOperator{first: ".", sep: "."}
+	case '/':
+		return templateOperator{first: "/", sep: "/"}
+	case ';':
+		return templateOperator{first: ";", sep: ";", named: true}
+	case '?':
+		return templateOperator{first: "?", sep: "&", named: true, ifEmpty: "="}
+	case '&':
+		return templateOperator{first: "&", sep: "&", named: true, ifEmpty: "="}
+	default:
+		return templateOperator{sep: ","}
+	}
+}
+
+// Expand substitutes values into the template and returns the resulting
+// string. Variables missing from values, or set to nil, are omitted.
+func (t *Template) Expand(values map[string]any) (string, error) {
+	var sb strings.Builder
+
+	for _, part := range t.parts {
+		if !part.isExpr {
+			sb.WriteString(part.literal)
+			continue
+		}
+
+		op := templateOperatorFor(part.op)
+
+		var rendered []string
+		for _, v := range part.vars {
+			raw, ok := values[v.name]
+			if !ok || raw == nil {
+				continue
+			}
+
+			s, defined := expandTemplateVar(v, raw, op)
+			if !defined {
+				continue
+			}
+			rendered = append(rendered, s)
+		}
+
+		if len(rendered) == 0 {
+			continue
+		}
+
+		sb.WriteString(op.first)
+		sb.WriteString(strings.Join(rendered, op.sep))
+	}
+
+	return sb.String(), nil
+}
+
+// Builder expands the template and parses the result into a Builder, so
+// that it can be further mutated before Build.
+func (t *Template) Builder(values map[string]any) (*Builder, error) {
+	s, err := t.Expand(values)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(s)
+}
+
+func expandTemplateVar(v templateVar, raw any, op templateOperator) (string, bool) {
+	switch value := normalizeTemplateValue(raw).(type) {
+	case string:
+		if value == "" {
+			if op.named {
+				return v.name + op.ifEmpty, true
+			}
+			return "", true
+		}
+
+		s := value
+		if v.prefix > 0 {
+			runes := []rune(s)
+			if v.prefix < len(runes) {
+				s = string(runes[:v.prefix])
+			}
+		}
+
+		encoded := pctEncodeTemplateValue(s, op.allowReserved)
+		if op.named {
+			return v.name + "=" + encoded, true
+		}
+		return encoded, true
+
+	case []string:
+		if len(value) == 0 {
+			return "", false
+		}
+
+		if v.explode {
+			items := make([]string, len(value))
+			for i, item := range value {
+				enc := pctEncodeTemplateValue(item, op.allowReserved)
+				if op.named {
+					if item == "" {
+						items[i] = v.name + op.ifEmpty
+					} else {
+						items[i] = v.name + "=" + enc
+					}
+				} else {
+					items[i] = enc
+				}
+			}
+			return strings.Join(items, op.sep), true
+		}
+
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = pctEncodeTemplateValue(item, op.allowReserved)
+		}
+		joined := strings.Join(items, ",")
+		if op.named {
+			return v.name + "=" + joined, true
+		}
+		return joined, true
+
+	case map[string]string:
+		if len(value) == 0 {
+			return "", false
+		}
+
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sortStrings(keys)
+
+		if v.explode {
+			items := make([]string, len(keys))
+			for i, k := range keys {
+				items[i] = pctEncodeTemplateValue(k, op.allowReserved) + "=" + pctEncodeTemplateValue(value[k], op.allowReserved)
+			}
+			return strings.Join(items, op.sep), true
+		}
+
+		items := make([]string, 0, len(keys)*2)
+		for _, k := range keys {
+			items = append(items, pctEncodeTemplateValue(k, op.allowReserved), pctEncodeTemplateValue(value[k], op.allowReserved))
+		}
+		joined := strings.Join(items, ",")
+		if op.named {
+			return v.name + "=" + joined, true
+		}
+		return joined, true
+
+	default:
+		return "", false
+	}
+}
+
+// normalizeTemplateValue coerces the loosely-typed values accepted by
+// Expand (string, []string, []any, map[string]string, map[string]any, or
+// any other value via fmt.Sprint) into the three shapes RFC 6570 defines:
+// string, list and associative array.
+func normalizeTemplateValue(raw any) any {
+	switch v := raw.(type) {
+	case string:
+		return v
+	case []string:
+		return v
+	case []any:
+		list := make([]string, len(v))
+		for i, e := range v {
+			list[i] = fmt.Sprint(e)
+		}
+		return list
+	case map[string]string:
+		return v
+	case map[string]any:
+		m := make(map[string]string, len(v))
+		for k, e := range v {
+			m[k] = fmt.Sprint(e)
+		}
+		return m
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortStrings(s []string) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && s[j-1] > s[j]; j-- {
+			s[j-1], s[j] = s[j], s[j-1]
+		}
+	}
+}
+
+func isTemplateUnreserved(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+		b == '-' || b == '.' || b == '_' || b == '~'
+}
+
+func isTemplateReserved(b byte) bool {
+	return strings.IndexByte(":/?#[]@!$&'()*+,;=", b) != -1
+}
+
+func isTemplateHex(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'A' && b <= 'F') || (b >= 'a' && b <= 'f')
+}
+
+// pctEncodeTemplateValue percent-encodes s for substitution into a URI
+// Template expansion, per RFC 6570 section 3.2.1. Existing pct-encoded
+// triplets are passed through unchanged; allowReserved additionally lets
+// reserved characters (as used by "+" and "#" expansions) through as-is.
+func pctEncodeTemplateValue(s string, allowReserved bool) string {
+	var sb strings.Builder
+
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		if c == '%' && i+2 < len(s) && isTemplateHex(s[i+1]) && isTemplateHex(s[i+2]) {
+			sb.WriteString(s[i : i+3])
+			i += 3
+			continue
+		}
+
+		if isTemplateUnreserved(c) || (allowReserved && isTemplateReserved(c)) {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+		i++
+	}
+
+	return sb.String()
+}
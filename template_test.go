@@ -0,0 +1,152 @@
+package url_builder
+
+import "testing"
+
+func TestTemplate_Expand(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		values  map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Simple string expansion",
+			pattern: "/users/{id}",
+			values:  map[string]any{"id": "123"},
+			want:    "/users/123",
+		},
+		{
+			name:    "Missing variable collapses to empty",
+			pattern: "/users/{id}",
+			values:  map[string]any{},
+			want:    "/users/",
+		},
+		{
+			name:    "Reserved expansion",
+			pattern: "{+path}/here",
+			values:  map[string]any{"path": "/foo/bar"},
+			want:    "/foo/bar/here",
+		},
+		{
+			name:    "Fragment expansion",
+			pattern: "X{#section}",
+			values:  map[string]any{"section": "one,two"},
+			want:    "X#one,two",
+		},
+		{
+			name:    "Path segment expansion",
+			pattern: "/users{/id,section}",
+			values:  map[string]any{"id": "123", "section": "posts"},
+			want:    "/users/123/posts",
+		},
+		{
+			name:    "Path-style parameter expansion",
+			pattern: "{;x,y}",
+			values:  map[string]any{"x": "1024", "y": "768"},
+			want:    ";x=1024;y=768",
+		},
+		{
+			name:    "Path-style parameter with empty value",
+			pattern: "{;x,empty}",
+			values:  map[string]any{"x": "1024", "empty": ""},
+			want:    ";x=1024;empty",
+		},
+		{
+			name:    "Form-style query expansion",
+			pattern: "/users{?tag,limit}",
+			values:  map[string]any{"tag": "go", "limit": "10"},
+			want:    "/users?tag=go&limit=10",
+		},
+		{
+			name:    "Form-style continuation",
+			pattern: "/users?active=true{&tag,limit}",
+			values:  map[string]any{"tag": "go", "limit": "10"},
+			want:    "/users?active=true&tag=go&limit=10",
+		},
+		{
+			name:    "List explode in query",
+			pattern: "/search{?tag*}",
+			values:  map[string]any{"tag": []string{"go", "url"}},
+			want:    "/search?tag=go&tag=url",
+		},
+		{
+			name:    "List without explode",
+			pattern: "/search{?tag}",
+			values:  map[string]any{"tag": []string{"go", "url"}},
+			want:    "/search?tag=go,url",
+		},
+		{
+			name:    "Map explode",
+			pattern: "{?params*}",
+			values:  map[string]any{"params": map[string]string{"a": "1", "b": "2"}},
+			want:    "?a=1&b=2",
+		},
+		{
+			name:    "Prefix modifier",
+			pattern: "/users/{id:3}",
+			values:  map[string]any{"id": "123456"},
+			want:    "/users/123",
+		},
+		{
+			name:    "Percent-encodes reserved characters by default",
+			pattern: "/search{?q}",
+			values:  map[string]any{"q": "a b/c"},
+			want:    "/search?q=a%20b%2Fc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := tmpl.Expand(tt.values)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_Builder(t *testing.T) {
+	tmpl, err := ParseTemplate("http://example.com/users/{id}{?tag}")
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+
+	b, err := tmpl.Builder(map[string]any{"id": "42", "tag": "go"})
+	if err != nil {
+		t.Fatalf("Builder() error = %v", err)
+	}
+
+	got, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := "http://example.com/users/42?tag=go"
+	if got != want {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+}
+
+func TestParseTemplate_InvalidExpression(t *testing.T) {
+	if _, err := ParseTemplate("/users/{"); err == nil {
+		t.Error("expected error for unterminated expression")
+	}
+	if _, err := ParseTemplate("/users/{}"); err == nil {
+		t.Error("expected error for empty expression")
+	}
+	if _, err := ParseTemplate("/users/{id:}"); err == nil {
+		t.Error("expected error for invalid prefix modifier")
+	}
+}